@@ -1,48 +1,82 @@
 // In order to properly satisfy the topology interface, the kubernetes wrapper
 // will monitor POD events (either starting, updating, or stopping) and match
-// them with information from the underlying CRI (Container Runtime Interface)
-// which is managed by the kubelet.
+// them with information from the underlying container runtime, which is
+// managed by the kubelet.
 //
-// We utilize the CRI endpoints to fetch the current PID, and PID namespace
+// We utilize a ContainerRuntime backend (CRI, containerd, Docker, or a
+// cgroup scan as a last resort) to fetch the current PID, and PID namespace
 // associated with every container in a POD. When any POD event is seen, this
-// code will automatically scan the CRI for containers that match these PODS.
+// code will automatically scan the runtime for containers that match these
+// PODS.
 package topology
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"reflect"
+	"sync"
 
 	"github.com/criticalstack/swoll/pkg/types"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
 	klient "k8s.io/client-go/rest"
 	kcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
-	pb "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 
 	"k8s.io/client-go/kubernetes"
 )
 
+// containerState is a snapshot of the fields of a kapi.ContainerStatus that
+// actually matter for deciding whether a container has transitioned, keyed
+// by container name within a pod.
+type containerState struct {
+	containerID string
+	running     bool
+	startedAt   metav1.Time
+}
+
+// podStatusEntry caches the last-seen PodStatus for a pod, along with the
+// per-container state derived from it, so that Run can tell the difference
+// between a meaningful container transition and update churn (managed-fields
+// bumps, readiness probe toggles, annotation edits, ...).
+type podStatusEntry struct {
+	status     kapi.PodStatus
+	containers map[string]containerState
+	resolved   map[string]*types.Container // last container info resolved for each still-relevant container name, used to emit Stop after a container can no longer be found via a live runtime query
+}
+
 type KubernetesOption func(*Kubernetes) error
 
 // Kubernetes contains all the working parts to facilitate a Observer
 // for kubernetes operation.
 type Kubernetes struct {
-	criSocket     string                // fully-qualified path to a CRI socket endpoint
-	kubeConfig    string                // if running out-of-cluster, the kubeconfig file
-	namespace     string                // only monitor events within a specific namespace
-	labelSelector string                // labels to match on
-	fieldSelector string                // fields to match on
-	procRoot      string                // if your /proc is outside of root, (as in /mnt/other/proc)
-	criClient     *grpc.ClientConn      // the CRI GRPC connection
-	kubeClient    *kubernetes.Clientset // the kube rpc connection
-	kubeWatcher   *kcache.ListWatch     // the listwatch client for monitoring pods
+	criSocket      string                     // fully-qualified path to a CRI socket endpoint
+	kubeConfig     string                     // if running out-of-cluster, the kubeconfig file
+	namespace      string                     // only monitor events within a specific namespace
+	labelSelector  string                     // labels to match on
+	fieldSelector  string                     // fields to match on
+	procRoot       string                     // if your /proc is outside of root, (as in /mnt/other/proc)
+	criClient      *grpc.ClientConn           // the CRI GRPC connection
+	kubeClient     *kubernetes.Clientset      // the kube rpc connection
+	kubeWatcher    *kcache.ListWatch          // the listwatch client for monitoring pods
+	podStatus      map[string]*podStatusEntry // last-seen status per "namespace/name", used to dedupe pod update churn
+	criEventStream bool                       // use the CRI's GetContainerEvents stream instead of the pod informer
+
+	workload          *workload          // workload to resolve a pod selector from, if any
+	workloadMu        sync.Mutex         // guards workloadSelectors, refreshed from a separate Jobs watch for CronJobs
+	workloadSelectors []klabels.Selector // for a CronJob workload, the union of its child Jobs' pod selectors
+
+	runtime ContainerRuntime // backend used to list running containers and resolve their pids
+
+	includeKinds map[Kind]bool // if non-empty, only emit containers whose Kind is in this set
 }
 
 // WithKubernetesNamespace sets the namespace configuration option
@@ -102,9 +136,34 @@ func WithKubernetesFieldSelector(f string) KubernetesOption {
 	}
 }
 
+// WithKubernetesCRIEventStream configures Run to prefer the CRI's
+// GetContainerEvents stream over the pod informer for detecting container
+// start/stop, falling back to the informer if the runtime doesn't implement
+// it, or if no CRI socket is configured via WithKubernetesCRI (the event
+// stream is a CRI-specific gRPC call, unrelated to the pluggable
+// ContainerRuntime backend used elsewhere).
+func WithKubernetesCRIEventStream(enabled bool) KubernetesOption {
+	return func(k *Kubernetes) error {
+		k.criEventStream = enabled
+		return nil
+	}
+}
+
+// WithKubernetesRuntime sets the ContainerRuntime backend used to list
+// running containers and resolve their pids, bypassing auto-detection.
+func WithKubernetesRuntime(runtime ContainerRuntime) KubernetesOption {
+	return func(k *Kubernetes) error {
+		k.runtime = runtime
+		return nil
+	}
+}
+
 // NewKubernetes creates a Observer object for watching kubernetes changes
 func NewKubernetes(opts ...KubernetesOption) (*Kubernetes, error) {
-	ret := &Kubernetes{namespace: kapi.NamespaceAll}
+	ret := &Kubernetes{
+		namespace: kapi.NamespaceAll,
+		podStatus: make(map[string]*podStatusEntry),
+	}
 
 	for _, opt := range opts {
 		if err := opt(ret); err != nil {
@@ -129,7 +188,10 @@ func (k *Kubernetes) connectCRI(ctx context.Context) error {
 	return nil
 }
 
-func (k *Kubernetes) connectKube(ctx context.Context) error {
+// connectKubeClient builds the typed kube client used to both talk to the
+// API server directly (e.g. to resolve a workload's pod selector) and to
+// build the pod list-watch below.
+func (k *Kubernetes) connectKubeClient(ctx context.Context) error {
 	var (
 		kclicfg *klient.Config
 		err     error
@@ -144,12 +206,19 @@ func (k *Kubernetes) connectKube(ctx context.Context) error {
 		return errors.Wrapf(err, "bad kube-config directive '%s'", k.kubeConfig)
 	}
 
-	if client, err := kubernetes.NewForConfig(kclicfg); err != nil {
+	client, err := kubernetes.NewForConfig(kclicfg)
+	if err != nil {
 		return err
-	} else {
-		k.kubeClient = client
 	}
 
+	k.kubeClient = client
+	return nil
+}
+
+// buildKubeWatcher (re)builds the pod list-watch from the currently resolved
+// namespace/labelSelector/fieldSelector. It must be called after any
+// workload selector resolution, since that can mutate k.labelSelector.
+func (k *Kubernetes) buildKubeWatcher() {
 	optionsModifier := func(options *metav1.ListOptions) {
 		if k.labelSelector != "" {
 			options.LabelSelector = k.labelSelector
@@ -165,131 +234,144 @@ func (k *Kubernetes) connectKube(ctx context.Context) error {
 		"pods",
 		k.namespace,
 		optionsModifier)
+}
 
-	return nil
+// connectKube builds the typed kube client, resolves any configured
+// workload selector against it, and (re)builds the pod list-watch from the
+// result.
+func (k *Kubernetes) connectKube(ctx context.Context) error {
+	if err := k.connectKubeClient(ctx); err != nil {
+		return err
+	}
+
+	if err := k.connectWorkload(ctx); err != nil {
+		return errors.Wrap(err, "resolving workload selector")
+	}
 
+	k.buildKubeWatcher()
+
+	return nil
 }
 
 // Connect will do all the things to create client connects to both the
 // kubernetes api, and the CRI grpc endpoint.
 func (k *Kubernetes) Connect(ctx context.Context) error {
-	if err := k.connectCRI(ctx); err != nil {
-		return errors.Wrapf(err, "failed to connect to CRI endpoint '%s'", k.criSocket)
+	if k.criSocket != "" {
+		if err := k.connectCRI(ctx); err != nil {
+			return errors.Wrapf(err, "failed to connect to CRI endpoint '%s'", k.criSocket)
+		}
 	}
 
 	if err := k.connectKube(ctx); err != nil {
 		return err
 	}
 
+	if err := k.connectRuntime(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// getContainerPid takes a container-id and attempts to find the PID of the
-// container using CRI from some of the meta-data found within the info section
-// of the response.
-func (k *Kubernetes) getContainerPid(ctx context.Context, id string) (int, error) {
-	rpc := pb.NewRuntimeServiceClient(k.criClient)
-	request := &pb.ContainerStatusRequest{ContainerId: id, Verbose: true}
-	response, err := rpc.ContainerStatus(ctx, request)
-
-	if err != nil {
-		return -1, err
+// connectRuntime resolves k.runtime if it isn't already set: an explicit
+// WithKubernetesRuntime takes priority, then the configured CRI socket, then
+// auto-detection against well-known runtime socket paths (falling back to a
+// cgroup scan as a last resort).
+func (k *Kubernetes) connectRuntime(ctx context.Context) error {
+	if k.runtime != nil {
+		return nil
 	}
 
-	rawinfo := response.GetInfo()
-	info := make(map[string]interface{})
-
-	if err := json.Unmarshal([]byte(rawinfo["info"]), &info); err != nil {
-		return -1, err
-	}
+	if k.criSocket != "" {
+		if k.criClient == nil {
+			if err := k.connectCRI(ctx); err != nil {
+				return errors.Wrapf(err, "failed to connect to CRI endpoint '%s'", k.criSocket)
+			}
+		}
 
-	if rawpid, ok := info["pid"]; ok {
-		return int(rawpid.(float64)), nil
+		k.runtime = newCRIRuntimeFromConn(k.criClient)
+		return nil
 	}
 
-	return -1, errors.New("no pid found in info response")
+	k.runtime = detectContainerRuntime(k.procRoot)
+	return nil
 }
 
 type matchPod struct {
 	podName      string
 	podNamespace string
+	podUID       string
 }
 
-// criContainers returns all running containers found in the CRI and attempts to
-// resolve the pod, kube-namespace, and kernel-namespace.
-func (k *Kubernetes) criContainers(ctx context.Context, match ...*matchPod) ([]*types.Container, error) {
-	if k.criClient == nil {
-		if err := k.connectCRI(ctx); err != nil {
-			return nil, err
-		}
+// runtimeContainers returns all running containers found via the configured
+// ContainerRuntime and attempts to resolve the pod, kube-namespace, and
+// kernel-namespace for each.
+func (k *Kubernetes) runtimeContainers(ctx context.Context, match ...*matchPod) ([]*types.Container, error) {
+	if err := k.connectRuntime(ctx); err != nil {
+		return nil, err
 	}
 
-	// we only care about containers that are marked as running
-	request := &pb.ListContainersRequest{
-		Filter: &pb.ContainerFilter{
-			State: &pb.ContainerStateValue{
-				State: pb.ContainerState_CONTAINER_RUNNING,
-			},
-		},
+	var filter *RuntimeFilter
+	if len(match) > 0 {
+		filter = &RuntimeFilter{PodName: match[0].podName, PodNamespace: match[0].podNamespace, PodUID: match[0].podUID}
 	}
 
-	rpc := pb.NewRuntimeServiceClient(k.criClient)
-	// make the rpc request for the containers
-	res, err := rpc.ListContainers(ctx, request)
+	containers, err := k.runtime.ListRunning(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	containers := res.GetContainers()
-	ret := make([]*types.Container, 0)
+	ret := make([]*types.Container, 0, len(containers))
 
 	for _, container := range containers {
-		labels := container.GetLabels()
+		labels := container.Labels
 
-		// we use the following attribute labels to associate cri info to the
-		// corresponding kube host.
+		// we use the following attribute labels to associate runtime info to
+		// the corresponding kube host.
 		pod := labels["io.kubernetes.pod.name"]
 		kns := labels["io.kubernetes.pod.namespace"]
 		name := labels["io.kubernetes.container.name"]
 
-		// if we have the optional match argument, only continue if this
-		// container matches.
-		if len(match) > 0 {
-			if pod == "" || kns == "" {
-				log.Printf("[warning] no kubernets namespace/pod found in CRI labels")
+		// richer backends expose pod identity via labels; the cgroup scan
+		// can't, but it does recover the owning pod's UID from the cgroup
+		// path itself, so fall back to the pod we matched against by UID
+		// instead of dropping the container outright.
+		if len(match) > 0 && (pod == "" || kns == "") {
+			if container.PodUID == "" || container.PodUID != match[0].podUID {
+				log.Printf("[warning] no kubernets namespace/pod found in container labels")
 				continue
 			}
 
-			m := match[0]
+			pod = match[0].podName
+			kns = match[0].podNamespace
 
-			if m.podName != pod || m.podNamespace != kns {
-				// this CRI container did not match the optional match argument,
-				// so skip insertion into our final result.
-				continue
-			}
+			// the per-container name still can't be recovered from the
+			// cgroup path alone; name stays empty for this backend.
 		}
 
-		id := container.GetId()
-		pid, err := k.getContainerPid(ctx, id)
-		if err != nil {
-			// could not find a pid for this container, warn and skip since we
-			// really can't do anything with this entry.
-			log.Printf("[warning] could not fetch pid for container '%s' (%v) .. skipping", id, err)
-			continue
+		pid := container.Pid
+		if pid == 0 {
+			pid, err = k.runtime.ContainerPID(ctx, container.ID)
+			if err != nil {
+				// could not find a pid for this container, warn and skip since we
+				// really can't do anything with this entry.
+				log.Printf("[warning] could not fetch pid for container '%s' (%v) .. skipping", container.ID, err)
+				continue
+			}
 		}
 
 		pidns, err := getPidNamespace(k.procRoot, pid)
 		if err != nil {
 			// could not fetch the pid-namespace of this container, warn and
 			// continue.
-			log.Printf("[warning] could not fetch pid-namespace for container '%s' (%v) .. skipping", id, err)
+			log.Printf("[warning] could not fetch pid-namespace for container '%s' (%v) .. skipping", container.ID, err)
 			continue
 		}
 
 		ret = append(ret, &types.Container{
-			ID:           id,
+			ID:           container.ID,
 			Labels:       labels,
-			Image:        container.GetImageRef(),
+			Image:        container.Image,
 			Pod:          pod,
 			Namespace:    kns,
 			Name:         name,
@@ -303,12 +385,18 @@ func (k *Kubernetes) criContainers(ctx context.Context, match ...*matchPod) ([]*
 
 // Containers returns an array of running containers inside kubernetes.
 func (k *Kubernetes) Containers(ctx context.Context) ([]*types.Container, error) {
-	return k.criContainers(ctx)
+	return k.runtimeContainers(ctx)
 }
 
 // Close frees up all the running resources of this Kubernetes observer
 func (k *Kubernetes) Close() error {
 	if k != nil {
+		if k.runtime != nil {
+			if err := k.runtime.Close(); err != nil {
+				return err
+			}
+		}
+
 		if k.criClient != nil {
 			if err := k.criClient.Close(); err != nil {
 				return err
@@ -321,18 +409,99 @@ func (k *Kubernetes) Close() error {
 
 // containersForPod returns a list of containers that match a pod.
 func (k *Kubernetes) containersForPod(ctx context.Context, pod *kapi.Pod) []*types.Container {
-	criContainers, err := k.criContainers(ctx, &matchPod{pod.Name, pod.Namespace})
+	containers, err := k.runtimeContainers(ctx, &matchPod{pod.Name, pod.Namespace, string(pod.UID)})
 	if err != nil {
-		log.Printf("[warning] failed to fetch CRI containers matching pod %s/%s: %v", pod.Name, pod.Namespace, err)
+		log.Printf("[warning] failed to fetch containers matching pod %s/%s: %v", pod.Name, pod.Namespace, err)
+	}
+
+	return containers
+}
+
+// podCacheKey returns the key used to store/retrieve a pod's cached status,
+// namespaced the same way kubelet's statusManager keys its cache.
+func podCacheKey(pod *kapi.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// containerStates builds a per-container-name view of a pod's container
+// statuses, capturing only the fields relevant to deciding whether a
+// container has actually transitioned. Regular, init, and ephemeral
+// (kubectl debug) containers are all included, keyed by name (which is
+// unique across the three within a pod spec), so that an init container
+// reaching Terminated or a debug session starting is seen as a transition
+// in its own right rather than being silently ignored or folded into a
+// full-pod stop/start.
+func containerStates(pod *kapi.Pod) map[string]containerState {
+	statuses := make([]kapi.ContainerStatus, 0, len(pod.Status.ContainerStatuses)+len(pod.Status.InitContainerStatuses)+len(pod.Status.EphemeralContainerStatuses))
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.EphemeralContainerStatuses...)
+
+	states := make(map[string]containerState, len(statuses))
+
+	for _, cs := range statuses {
+		state := containerState{containerID: cs.ContainerID}
+
+		if cs.State.Running != nil {
+			state.running = true
+			state.startedAt = cs.State.Running.StartedAt
+		}
+
+		states[cs.Name] = state
 	}
 
-	return criContainers
+	return states
+}
+
+// diffContainerStates compares the previous and current per-container state
+// of a pod and reports which containers newly transitioned out of Running
+// (stopped) and which newly transitioned into Running (started). Containers
+// whose relevant state did not change are ignored entirely, which is what
+// lets Run ignore pod update churn that doesn't affect any container.
+func diffContainerStates(old, updated map[string]containerState) (stopped, started []string) {
+	for name, n := range updated {
+		o, existed := old[name]
+		if existed && reflect.DeepEqual(o, n) {
+			continue
+		}
+
+		if n.running && (!existed || !o.running || o.containerID != n.containerID) {
+			started = append(started, name)
+		}
+	}
+
+	for name, o := range old {
+		n, exists := updated[name]
+		if exists && reflect.DeepEqual(o, n) {
+			continue
+		}
+
+		if o.running && (!exists || !n.running || n.containerID != o.containerID) {
+			stopped = append(stopped, name)
+		}
+	}
+
+	return stopped, started
+}
+
+// containsName reports whether name is present in names.
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Run connects to kube and watches for POD changes. When changes are seen,
 // attempt to match the changes with the underlying CRI containers (to find the
 // running PID of the container, and the underlying PID namespace).
 func (k *Kubernetes) Run(ctx context.Context, out chan<- *ObservationEvent) {
+	// Connect the kube client (if Run is being called standalone, without a
+	// prior successful Connect) before anything below that dereferences it,
+	// including watchCronJobJobs's goroutine just below.
 	if k.kubeWatcher == nil {
 		if err := k.connectKube(ctx); err != nil {
 			log.Printf("[warning] error connecting to kubernetes: %v", err)
@@ -340,43 +509,183 @@ func (k *Kubernetes) Run(ctx context.Context, out chan<- *ObservationEvent) {
 		}
 	}
 
+	// Started before the criEventStream branch below (which can return
+	// without ever reaching the informer setup further down) since a
+	// CronJob workload's selectors need refreshing as its child Jobs come
+	// and go regardless of which path is emitting events.
+	if k.workload != nil && k.workload.kind == WorkloadCronJob {
+		go k.watchCronJobJobs(ctx)
+	}
+
+	if k.criEventStream && k.criSocket == "" {
+		log.Printf("[info] CRI event stream requested but no CRI socket configured, falling back to pod informer")
+	} else if k.criEventStream {
+		if k.criClient == nil {
+			if err := k.connectCRI(ctx); err != nil {
+				log.Printf("[warning] error connecting to CRI, falling back to pod informer: %v", err)
+			}
+		}
+
+		if k.criClient != nil {
+			err := k.runCRIEventStream(ctx, out)
+
+			if status.Code(err) != codes.Unimplemented {
+				if err != nil && ctx.Err() == nil {
+					log.Printf("[warning] CRI event stream ended: %v", err)
+				}
+
+				return
+			}
+
+			log.Printf("[info] CRI runtime does not implement GetContainerEvents, falling back to pod informer")
+		}
+	}
+
 	_, informer := kcache.NewInformer(k.kubeWatcher, &kapi.Pod{}, 0, kcache.ResourceEventHandlerFuncs{
 		UpdateFunc: func(obj interface{}, newobj interface{}) {
-			// We treat an update in two separate messages if the old status
-			// does not equal the new status, and the new status is `Running`.
 			oldpod := obj.(*kapi.Pod)
 			newpod := newobj.(*kapi.Pod)
 
-			for _, c := range k.containersForPod(ctx, oldpod) {
-				log.Printf("[info] (update) removing %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
+			if !k.podMatchesWorkload(newpod) {
+				return
+			}
 
-				out <- &ObservationEvent{EventTypeStop, c}
+			key := podCacheKey(newpod)
+			cached, hasCached := k.podStatus[key]
+
+			// Prefer our own cached view of the previous state over the
+			// informer's oldpod, since newStates below may have already
+			// pinned startedAt to the value we first observed.
+			oldStates := containerStates(oldpod)
+			resolved := make(map[string]*types.Container)
+			if hasCached {
+				oldStates = cached.containers
+
+				for name, c := range cached.resolved {
+					resolved[name] = c
+				}
+			}
+
+			newStates := containerStates(newpod)
+			for name, ns := range newStates {
+				if prev, ok := oldStates[name]; ok && prev.containerID == ns.containerID {
+					// A container-id's startedAt is stable once observed;
+					// don't let a republished status reset it.
+					ns.startedAt = prev.startedAt
+					newStates[name] = ns
+				}
 			}
 
-			log.Printf("[info] (update) oldStatus=%v, newStatus=%v\n", oldpod.Status.Phase, newpod.Status.Phase)
+			stopped, started := diffContainerStates(oldStates, newStates)
 
-			if newpod.Status.Phase == kapi.PodRunning {
+			// A restarted container (same name, new container-id) is in
+			// both lists; keep the info cached for its old instance so the
+			// loop below can't overwrite it before the Stop is emitted.
+			prevResolved := make(map[string]*types.Container, len(resolved))
+			for name, c := range resolved {
+				prevResolved[name] = c
+			}
+
+			if len(started) > 0 {
 				for _, c := range k.containersForPod(ctx, newpod) {
-					log.Printf("[info] (update) adding %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
+					if !containsName(started, c.Name) {
+						continue
+					}
+
+					resolved[c.Name] = c
+
+					if !k.shouldEmit(newpod, c) {
+						continue
+					}
 
+					log.Printf("[info] (update) adding %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
 					out <- &ObservationEvent{EventTypeStart, c}
 				}
 			}
+
+			// A container that just stopped is, by definition, no longer
+			// running, so it can no longer be found via a live runtime
+			// query -- emit it from the last resolved info we cached for it
+			// instead. This is what makes a terminated init container (and
+			// any other container that stops without the whole pod being
+			// torn down) emit its own Stop instead of going unnoticed or
+			// being folded into a blanket removal of every container in the
+			// pod.
+			for _, name := range stopped {
+				c, ok := prevResolved[name]
+				if !ok {
+					log.Printf("[warning] no cached container info for stopped container '%s' in pod %s/%s .. skipping", name, newpod.Namespace, newpod.Name)
+					continue
+				}
+
+				if resolved[name] == c {
+					delete(resolved, name)
+				}
+
+				if !k.shouldEmit(newpod, c) {
+					continue
+				}
+
+				log.Printf("[info] (update) removing %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
+				out <- &ObservationEvent{EventTypeStop, c}
+			}
+
+			k.podStatus[key] = &podStatusEntry{status: newpod.Status, containers: newStates, resolved: resolved}
 		},
 		DeleteFunc: func(obj interface{}) {
-			for _, c := range k.containersForPod(ctx, obj.(*kapi.Pod)) {
+			pod := obj.(*kapi.Pod)
+
+			if !k.podMatchesWorkload(pod) {
+				return
+			}
+
+			key := podCacheKey(pod)
+
+			containers := k.containersForPod(ctx, pod)
+			if cached, ok := k.podStatus[key]; ok {
+				for _, c := range cached.resolved {
+					containers = append(containers, c)
+				}
+			}
+
+			seen := make(map[string]bool, len(containers))
+
+			for _, c := range containers {
+				if seen[c.Name] || !k.shouldEmit(pod, c) {
+					continue
+				}
+
+				seen[c.Name] = true
+
 				log.Printf("[info] removing %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
 
 				out <- &ObservationEvent{EventTypeStop, c}
 			}
+
+			delete(k.podStatus, key)
 		},
 		AddFunc: func(obj interface{}) {
-			for _, c := range k.containersForPod(ctx, obj.(*kapi.Pod)) {
+			pod := obj.(*kapi.Pod)
+
+			if !k.podMatchesWorkload(pod) {
+				return
+			}
+
+			resolved := make(map[string]*types.Container)
+
+			for _, c := range k.containersForPod(ctx, pod) {
+				resolved[c.Name] = c
+
+				if !k.shouldEmit(pod, c) {
+					continue
+				}
+
 				log.Printf("[info] adding %s.%s.%s\n", c.Name, c.Pod, c.Namespace)
 
 				out <- &ObservationEvent{EventTypeStart, c}
 			}
 
+			k.podStatus[podCacheKey(pod)] = &podStatusEntry{status: pod.Status, containers: containerStates(pod), resolved: resolved}
 		},
 	})
 
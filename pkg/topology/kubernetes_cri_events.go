@@ -0,0 +1,180 @@
+package topology
+
+import (
+	"context"
+	"log"
+
+	"github.com/criticalstack/swoll/pkg/types"
+	kapi "k8s.io/api/core/v1"
+	kfields "k8s.io/apimachinery/pkg/fields"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	pb "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// runCRIEventStream opens the CRI's GetContainerEvents stream and translates
+// every event it receives into an ObservationEvent on out, honoring the
+// namespace/label/field filters configured on k. It blocks until the stream
+// ends, returning the gRPC status error that ended it (codes.Canceled on a
+// normal shutdown via ctx, or notably codes.Unimplemented on runtimes that
+// don't support the stream, such as older CRI-O or dockershim-based setups)
+// so that Run can decide whether to fall back to the pod informer.
+func (k *Kubernetes) runCRIEventStream(ctx context.Context, out chan<- *ObservationEvent) error {
+	rpc := pb.NewRuntimeServiceClient(k.criClient)
+
+	stream, err := rpc.GetContainerEvents(ctx, &pb.ContainerEventsRequest{})
+	if err != nil {
+		return err
+	}
+
+	resolved := make(map[string]*types.Container) // last container info resolved per id, since a stopped/deleted container's pid is no longer live
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		k.handleContainerEvent(ctx, event, resolved, out)
+	}
+}
+
+// handleContainerEvent translates a single CRI container event into zero or
+// more ObservationEvents. Unlike the informer path, the event already carries
+// the pod sandbox and per-container statuses, so no extra ListContainers/
+// ContainerStatus round-trip is needed beyond resolving the pid. resolved
+// caches the container info seen at CONTAINER_STARTED_EVENT time, keyed by
+// container id, since a CONTAINER_STOPPED_EVENT/CONTAINER_DELETED_EVENT is
+// for a container that has, by definition, already exited -- its pid can no
+// longer be resolved live, so the Stop event is built from the cached info
+// instead.
+func (k *Kubernetes) handleContainerEvent(ctx context.Context, event *pb.ContainerEventResponse, resolved map[string]*types.Container, out chan<- *ObservationEvent) {
+	sandbox := event.GetPodSandboxStatus()
+	if sandbox == nil {
+		return
+	}
+
+	labels := sandbox.GetLabels()
+	pod := labels["io.kubernetes.pod.name"]
+	kns := labels["io.kubernetes.pod.namespace"]
+
+	if !k.matchesSelectors(pod, kns, labels) {
+		return
+	}
+
+	eventType := event.GetContainerEventType()
+
+	for _, cs := range event.GetContainersStatuses() {
+		id := cs.GetId()
+
+		switch eventType {
+		case pb.ContainerEventType_CONTAINER_CREATED_EVENT:
+			// Not yet running, nothing to observe until it starts.
+		case pb.ContainerEventType_CONTAINER_STARTED_EVENT:
+			container, err := k.containerFromStatus(ctx, cs, pod, kns)
+			if err != nil {
+				log.Printf("[warning] could not resolve pid for container '%s' from CRI event stream (%v) .. skipping", id, err)
+				continue
+			}
+
+			resolved[id] = container
+
+			if !k.classifyCRIEvent(ctx, container, cs.GetLabels()) {
+				continue
+			}
+
+			log.Printf("[info] (cri-event) adding %s.%s.%s\n", container.Name, container.Pod, container.Namespace)
+			out <- &ObservationEvent{EventTypeStart, container}
+		case pb.ContainerEventType_CONTAINER_STOPPED_EVENT, pb.ContainerEventType_CONTAINER_DELETED_EVENT:
+			container, ok := resolved[id]
+			if !ok {
+				log.Printf("[warning] no cached container info for stopped container '%s' from CRI event stream .. skipping", id)
+				continue
+			}
+
+			delete(resolved, id)
+
+			if !k.includesKind(container.Kind) {
+				continue
+			}
+
+			log.Printf("[info] (cri-event) removing %s.%s.%s\n", container.Name, container.Pod, container.Namespace)
+			out <- &ObservationEvent{EventTypeStop, container}
+		}
+	}
+}
+
+// containerFromStatus builds a types.Container from a CRI ContainerStatus
+// pulled off the event stream, resolving its pid the same way the CRI
+// ContainerRuntime backend does for the informer-driven path.
+func (k *Kubernetes) containerFromStatus(ctx context.Context, cs *pb.ContainerStatus, pod, namespace string) (*types.Container, error) {
+	id := cs.GetId()
+
+	pid, err := newCRIRuntimeFromConn(k.criClient).ContainerPID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pidns, err := getPidNamespace(k.procRoot, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Container{
+		ID:           id,
+		Labels:       cs.GetLabels(),
+		Image:        cs.GetImage().GetImage(),
+		Pod:          pod,
+		Namespace:    namespace,
+		Name:         cs.GetMetadata().GetName(),
+		Pid:          pid,
+		PidNamespace: pidns,
+	}, nil
+}
+
+// matchesSelectors reports whether a pod identified by name/namespace/labels
+// satisfies the namespace, labelSelector, fieldSelector, and workload
+// selector configured on k. The CRI event stream bypasses the
+// kube-apiserver's own list/watch filtering, so this has to be re-applied by
+// hand against the labels attached to the pod sandbox. The fieldSelector is
+// evaluated against metadata.name and metadata.namespace only; a term on any
+// other field is compared against an empty value.
+func (k *Kubernetes) matchesSelectors(pod, namespace string, labels map[string]string) bool {
+	if pod == "" || namespace == "" {
+		return false
+	}
+
+	if k.namespace != kapi.NamespaceAll && k.namespace != namespace {
+		return false
+	}
+
+	if !k.labelsMatchWorkload(labels) {
+		return false
+	}
+
+	if k.labelSelector != "" {
+		sel, err := klabels.Parse(k.labelSelector)
+		if err != nil {
+			log.Printf("[warning] invalid label selector '%s': %v", k.labelSelector, err)
+			return false
+		}
+
+		if !sel.Matches(klabels.Set(labels)) {
+			return false
+		}
+	}
+
+	if k.fieldSelector != "" {
+		sel, err := kfields.ParseSelector(k.fieldSelector)
+		if err != nil {
+			log.Printf("[warning] invalid field selector '%s': %v", k.fieldSelector, err)
+			return false
+		}
+
+		fields := kfields.Set{"metadata.name": pod, "metadata.namespace": namespace}
+		if !sel.Matches(fields) {
+			return false
+		}
+	}
+
+	return true
+}
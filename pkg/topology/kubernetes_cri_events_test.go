@@ -0,0 +1,98 @@
+package topology
+
+import (
+	"testing"
+
+	kapi "k8s.io/api/core/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+func TestMatchesSelectors(t *testing.T) {
+	cases := []struct {
+		name    string
+		k       *Kubernetes
+		pod, ns string
+		labels  map[string]string
+		want    bool
+	}{
+		{
+			name: "missing pod or namespace never matches",
+			k:    &Kubernetes{namespace: kapi.NamespaceAll},
+			pod:  "", ns: "default",
+			want: false,
+		},
+		{
+			name: "namespace mismatch",
+			k:    &Kubernetes{namespace: "kube-system"},
+			pod:  "web-1", ns: "default",
+			want: false,
+		},
+		{
+			name: "namespace match, no other filters",
+			k:    &Kubernetes{namespace: kapi.NamespaceAll},
+			pod:  "web-1", ns: "default",
+			want: true,
+		},
+		{
+			name:   "label selector matches",
+			k:      &Kubernetes{namespace: kapi.NamespaceAll, labelSelector: "app=web"},
+			pod:    "web-1", ns: "default",
+			labels: map[string]string{"app": "web"},
+			want:   true,
+		},
+		{
+			name:   "label selector mismatch",
+			k:      &Kubernetes{namespace: kapi.NamespaceAll, labelSelector: "app=web"},
+			pod:    "web-1", ns: "default",
+			labels: map[string]string{"app": "other"},
+			want:   false,
+		},
+		{
+			name:   "invalid label selector never matches",
+			k:      &Kubernetes{namespace: kapi.NamespaceAll, labelSelector: "==="},
+			pod:    "web-1", ns: "default",
+			want:   false,
+		},
+		{
+			name: "field selector matches pod name",
+			k:    &Kubernetes{namespace: kapi.NamespaceAll, fieldSelector: "metadata.name=web-1"},
+			pod:  "web-1", ns: "default",
+			want: true,
+		},
+		{
+			name: "field selector mismatch",
+			k:    &Kubernetes{namespace: kapi.NamespaceAll, fieldSelector: "metadata.name=web-2"},
+			pod:  "web-1", ns: "default",
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.k.matchesSelectors(tc.pod, tc.ns, tc.labels); got != tc.want {
+				t.Errorf("matchesSelectors(%q, %q, %v) = %v, want %v", tc.pod, tc.ns, tc.labels, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchesSelectorsAppliesWorkloadFilter(t *testing.T) {
+	sel, err := klabels.Parse("app=foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k := &Kubernetes{
+		namespace:         kapi.NamespaceAll,
+		workload:          &workload{kind: WorkloadCronJob},
+		workloadSelectors: []klabels.Selector{sel},
+	}
+
+	if !k.matchesSelectors("job-1", "default", map[string]string{"app": "foo"}) {
+		t.Error("expected a pod matching the CronJob's child Job selector to match")
+	}
+
+	if k.matchesSelectors("job-2", "default", map[string]string{"app": "bar"}) {
+		t.Error("expected a pod not matching any child Job selector to not match, same as podMatchesWorkload")
+	}
+}
@@ -0,0 +1,108 @@
+package topology
+
+import (
+	"context"
+	"log"
+
+	"github.com/criticalstack/swoll/pkg/types"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kind classifies a container by its role within a pod. It is an alias for
+// types.Kind so that the Kind field set on a *types.Container and the kinds
+// accepted by WithKubernetesIncludeKinds are the exact same type.
+type Kind = types.Kind
+
+const (
+	KindSandbox   = types.KindSandbox
+	KindInit      = types.KindInit
+	KindApp       = types.KindApp
+	KindEphemeral = types.KindEphemeral
+)
+
+// WithKubernetesIncludeKinds restricts emitted ObservationEvents to
+// containers of the given kinds. With no kinds given (the default), every
+// kind is emitted.
+func WithKubernetesIncludeKinds(kinds ...Kind) KubernetesOption {
+	return func(k *Kubernetes) error {
+		k.includeKinds = make(map[Kind]bool, len(kinds))
+
+		for _, kind := range kinds {
+			k.includeKinds[kind] = true
+		}
+
+		return nil
+	}
+}
+
+// includesKind reports whether kind passes the configured
+// WithKubernetesIncludeKinds filter.
+func (k *Kubernetes) includesKind(kind Kind) bool {
+	if len(k.includeKinds) == 0 {
+		return true
+	}
+
+	return k.includeKinds[kind]
+}
+
+// ContainerKind classifies a container belonging to pod, using its CRI
+// labels to recognize the pod sandbox and pod.Spec to distinguish init and
+// ephemeral (kubectl debug) containers from regular app containers.
+func ContainerKind(pod *kapi.Pod, labels map[string]string, containerName string) Kind {
+	if labels["io.kubernetes.container.name"] == "POD" {
+		return KindSandbox
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == containerName {
+			return KindInit
+		}
+	}
+
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == containerName {
+			return KindEphemeral
+		}
+	}
+
+	return KindApp
+}
+
+// shouldEmit classifies c's Kind (belonging to pod) and reports whether it
+// passes the configured kind filter.
+func (k *Kubernetes) shouldEmit(pod *kapi.Pod, c *types.Container) bool {
+	c.Kind = ContainerKind(pod, c.Labels, c.Name)
+	return k.includesKind(c.Kind)
+}
+
+// classifyCRIEvent sets container.Kind for a container observed via the CRI
+// event stream and reports whether it passes the configured kind filter.
+// Unlike shouldEmit, there's no *kapi.Pod in hand here -- only the CRI
+// labels attached to the event -- which is enough to recognize the pod
+// sandbox but not to tell an init or ephemeral container apart from a
+// regular one. A kind filter that needs that distinction only pays for a
+// Pods Get per container event when one is actually configured, since the
+// common case (no filter) has no need to classify at all.
+func (k *Kubernetes) classifyCRIEvent(ctx context.Context, container *types.Container, labels map[string]string) bool {
+	if labels["io.kubernetes.container.name"] == "POD" {
+		container.Kind = KindSandbox
+	} else {
+		container.Kind = KindApp
+	}
+
+	if len(k.includeKinds) == 0 {
+		return true
+	}
+
+	if container.Kind != KindSandbox {
+		pod, err := k.kubeClient.CoreV1().Pods(container.Namespace).Get(ctx, container.Pod, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("[warning] could not resolve pod %s/%s to classify container kind from CRI event stream, assuming App (%v)", container.Namespace, container.Pod, err)
+		} else {
+			container.Kind = ContainerKind(pod, labels, container.Name)
+		}
+	}
+
+	return k.includesKind(container.Kind)
+}
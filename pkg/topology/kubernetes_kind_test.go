@@ -0,0 +1,66 @@
+package topology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/criticalstack/swoll/pkg/types"
+	kapi "k8s.io/api/core/v1"
+)
+
+func TestContainerKind(t *testing.T) {
+	pod := &kapi.Pod{Spec: kapi.PodSpec{
+		InitContainers:      []kapi.Container{{Name: "init"}},
+		Containers:          []kapi.Container{{Name: "app"}},
+		EphemeralContainers: []kapi.EphemeralContainer{{EphemeralContainerCommon: kapi.EphemeralContainerCommon{Name: "debugger"}}},
+	}}
+
+	cases := []struct {
+		name          string
+		labels        map[string]string
+		containerName string
+		want          Kind
+	}{
+		{"sandbox", map[string]string{"io.kubernetes.container.name": "POD"}, "", KindSandbox},
+		{"init", nil, "init", KindInit},
+		{"app", nil, "app", KindApp},
+		{"ephemeral", nil, "debugger", KindEphemeral},
+		{"unrecognized name falls back to app", nil, "sidecar", KindApp},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ContainerKind(pod, tc.labels, tc.containerName); got != tc.want {
+				t.Errorf("ContainerKind() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyCRIEvent(t *testing.T) {
+	t.Run("sandbox is recognized from labels alone, no kube client needed", func(t *testing.T) {
+		k := &Kubernetes{includeKinds: map[Kind]bool{KindSandbox: true}}
+		c := &types.Container{Namespace: "default", Pod: "web-1"}
+
+		if !k.classifyCRIEvent(context.Background(), c, map[string]string{"io.kubernetes.container.name": "POD"}) {
+			t.Error("expected a sandbox container to pass a KindSandbox filter without needing pod.Spec")
+		}
+
+		if c.Kind != KindSandbox {
+			t.Errorf("Kind = %v, want %v", c.Kind, KindSandbox)
+		}
+	})
+
+	t.Run("no kind filter configured short-circuits without needing pod.Spec", func(t *testing.T) {
+		k := &Kubernetes{}
+		c := &types.Container{Namespace: "default", Pod: "web-1"}
+
+		if !k.classifyCRIEvent(context.Background(), c, nil) {
+			t.Error("expected no filter configured to always pass")
+		}
+
+		if c.Kind != KindApp {
+			t.Errorf("Kind = %v, want %v (best-effort default without pod.Spec)", c.Kind, KindApp)
+		}
+	})
+}
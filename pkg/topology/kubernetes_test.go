@@ -0,0 +1,118 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func running(id string, startedAt metav1.Time) kapi.ContainerStatus {
+	return kapi.ContainerStatus{
+		ContainerID: id,
+		State:       kapi.ContainerState{Running: &kapi.ContainerStateRunning{StartedAt: startedAt}},
+	}
+}
+
+func terminated(id string) kapi.ContainerStatus {
+	return kapi.ContainerStatus{
+		ContainerID: id,
+		State:       kapi.ContainerState{Terminated: &kapi.ContainerStateTerminated{}},
+	}
+}
+
+func TestContainerStates(t *testing.T) {
+	at := metav1.Now()
+
+	pod := &kapi.Pod{Status: kapi.PodStatus{
+		ContainerStatuses:          []kapi.ContainerStatus{withName(running("app-1", at), "app")},
+		InitContainerStatuses:      []kapi.ContainerStatus{withName(terminated("init-1"), "init")},
+		EphemeralContainerStatuses: []kapi.ContainerStatus{withName(running("debug-1", at), "debugger")},
+	}}
+
+	states := containerStates(pod)
+
+	want := map[string]containerState{
+		"app":      {containerID: "app-1", running: true, startedAt: at},
+		"init":     {containerID: "init-1", running: false},
+		"debugger": {containerID: "debug-1", running: true, startedAt: at},
+	}
+
+	if !reflect.DeepEqual(states, want) {
+		t.Fatalf("containerStates() = %#v, want %#v", states, want)
+	}
+}
+
+func withName(cs kapi.ContainerStatus, name string) kapi.ContainerStatus {
+	cs.Name = name
+	return cs
+}
+
+func TestDiffContainerStates(t *testing.T) {
+	at := metav1.Now()
+
+	cases := []struct {
+		name        string
+		old, new    map[string]containerState
+		wantStopped []string
+		wantStarted []string
+	}{
+		{
+			name:        "no change is ignored",
+			old:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			new:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			wantStopped: nil,
+			wantStarted: nil,
+		},
+		{
+			name:        "new container starts",
+			old:         map[string]containerState{},
+			new:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			wantStopped: nil,
+			wantStarted: []string{"app"},
+		},
+		{
+			name:        "container stops without the pod going away",
+			old:         map[string]containerState{"init": {containerID: "1", running: true, startedAt: at}},
+			new:         map[string]containerState{"init": {containerID: "1", running: false}},
+			wantStopped: []string{"init"},
+			wantStarted: nil,
+		},
+		{
+			name:        "container removed from status entirely while running",
+			old:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			new:         map[string]containerState{},
+			wantStopped: []string{"app"},
+			wantStarted: nil,
+		},
+		{
+			name:        "containerID changes while running is a restart, both stop and start",
+			old:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			new:         map[string]containerState{"app": {containerID: "2", running: true, startedAt: at}},
+			wantStopped: []string{"app"},
+			wantStarted: []string{"app"},
+		},
+		{
+			name:        "readiness-only update with an unchanged containerID is not a transition",
+			old:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			new:         map[string]containerState{"app": {containerID: "1", running: true, startedAt: at}},
+			wantStopped: nil,
+			wantStarted: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stopped, started := diffContainerStates(tc.old, tc.new)
+
+			if !reflect.DeepEqual(stopped, tc.wantStopped) {
+				t.Errorf("stopped = %v, want %v", stopped, tc.wantStopped)
+			}
+
+			if !reflect.DeepEqual(started, tc.wantStarted) {
+				t.Errorf("started = %v, want %v", started, tc.wantStarted)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+	kbatchv1 "k8s.io/api/batch/v1"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfields "k8s.io/apimachinery/pkg/fields"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kcache "k8s.io/client-go/tools/cache"
+)
+
+// WorkloadKind identifies the kind of higher-level workload resource that a
+// set of pods belongs to.
+type WorkloadKind string
+
+const (
+	WorkloadDeployment  WorkloadKind = "Deployment"
+	WorkloadStatefulSet WorkloadKind = "StatefulSet"
+	WorkloadDaemonSet   WorkloadKind = "DaemonSet"
+	WorkloadReplicaSet  WorkloadKind = "ReplicaSet"
+	WorkloadJob         WorkloadKind = "Job"
+	WorkloadCronJob     WorkloadKind = "CronJob"
+)
+
+// ParseKind normalizes the user-friendly aliases accepted by
+// WithKubernetesWorkload (e.g. "deploy", "sts", "ds") into a WorkloadKind.
+func ParseKind(kind string) (WorkloadKind, error) {
+	switch strings.ToLower(kind) {
+	case "deploy", "deployment":
+		return WorkloadDeployment, nil
+	case "sts", "statefulset":
+		return WorkloadStatefulSet, nil
+	case "ds", "daemonset":
+		return WorkloadDaemonSet, nil
+	case "rs", "replicaset":
+		return WorkloadReplicaSet, nil
+	case "job":
+		return WorkloadJob, nil
+	case "cronjob":
+		return WorkloadCronJob, nil
+	default:
+		return "", fmt.Errorf("unknown workload kind '%s'", kind)
+	}
+}
+
+// workload identifies a single workload resource to resolve a pod selector
+// from.
+type workload struct {
+	kind      WorkloadKind
+	namespace string
+	name      string
+}
+
+// WithKubernetesWorkload restricts the Kubernetes observer to pods belonging
+// to a specific Deployment, StatefulSet, DaemonSet, ReplicaSet, Job, or
+// CronJob, resolved via the workload's own selector instead of requiring the
+// user to hand-craft a label query. The resolved selector is merged with any
+// labelSelector set via WithKubernetesLabelSelector.
+func WithKubernetesWorkload(kind, namespace, name string) KubernetesOption {
+	return func(k *Kubernetes) error {
+		parsed, err := ParseKind(kind)
+		if err != nil {
+			return err
+		}
+
+		k.workload = &workload{kind: parsed, namespace: namespace, name: name}
+		return nil
+	}
+}
+
+// connectWorkload resolves k.workload (if set) into either a merged
+// k.labelSelector (for workload kinds with a single pod selector of their
+// own) or an initial set of k.workloadSelectors (for CronJob, whose pods are
+// only reachable through its child Jobs).
+func (k *Kubernetes) connectWorkload(ctx context.Context) error {
+	if k.workload == nil {
+		return nil
+	}
+
+	// a workload's pods all live in its own namespace
+	if k.namespace == kapi.NamespaceAll {
+		k.namespace = k.workload.namespace
+	}
+
+	if k.workload.kind == WorkloadCronJob {
+		return k.refreshCronJobSelectors(ctx)
+	}
+
+	selector, err := k.resolveWorkloadSelector(ctx, k.workload)
+	if err != nil {
+		return err
+	}
+
+	k.labelSelector = mergeRawSelector(k.labelSelector, selector)
+	return nil
+}
+
+// resolveWorkloadSelector fetches w's pod selector from the API server and
+// returns it as a label selector string.
+func (k *Kubernetes) resolveWorkloadSelector(ctx context.Context, w *workload) (string, error) {
+	var selector *metav1.LabelSelector
+
+	switch w.kind {
+	case WorkloadDeployment:
+		obj, err := k.kubeClient.AppsV1().Deployments(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving deployment %s/%s", w.namespace, w.name)
+		}
+		selector = obj.Spec.Selector
+	case WorkloadStatefulSet:
+		obj, err := k.kubeClient.AppsV1().StatefulSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving statefulset %s/%s", w.namespace, w.name)
+		}
+		selector = obj.Spec.Selector
+	case WorkloadDaemonSet:
+		obj, err := k.kubeClient.AppsV1().DaemonSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving daemonset %s/%s", w.namespace, w.name)
+		}
+		selector = obj.Spec.Selector
+	case WorkloadReplicaSet:
+		obj, err := k.kubeClient.AppsV1().ReplicaSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving replicaset %s/%s", w.namespace, w.name)
+		}
+		selector = obj.Spec.Selector
+	case WorkloadJob:
+		obj, err := k.kubeClient.BatchV1().Jobs(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving job %s/%s", w.namespace, w.name)
+		}
+		selector = obj.Spec.Selector
+	default:
+		return "", fmt.Errorf("unsupported workload kind '%s'", w.kind)
+	}
+
+	if selector == nil {
+		return "", nil
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", errors.Wrapf(err, "bad pod selector on %s %s/%s", w.kind, w.namespace, w.name)
+	}
+
+	return sel.String(), nil
+}
+
+// refreshCronJobSelectors recomputes k.workloadSelectors as the set of pod
+// selectors belonging to every Job currently owned by the configured
+// CronJob. A CronJob has no pod selector of its own -- each scheduled run
+// creates a new child Job, so the set of pods it covers has to be derived by
+// unioning its children's selectors, re-resolved as those children come and
+// go.
+func (k *Kubernetes) refreshCronJobSelectors(ctx context.Context) error {
+	w := k.workload
+
+	cronJob, err := k.kubeClient.BatchV1().CronJobs(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "resolving cronjob %s/%s", w.namespace, w.name)
+	}
+
+	jobs, err := k.kubeClient.BatchV1().Jobs(w.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "listing jobs for cronjob %s/%s", w.namespace, w.name)
+	}
+
+	selectors := make([]klabels.Selector, 0, len(jobs.Items))
+
+	for _, job := range jobs.Items {
+		if !isOwnedBy(job.OwnerReferences, cronJob.UID) || job.Spec.Selector == nil {
+			continue
+		}
+
+		sel, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			log.Printf("[warning] bad pod selector on job %s/%s: %v", job.Namespace, job.Name, err)
+			continue
+		}
+
+		selectors = append(selectors, sel)
+	}
+
+	k.workloadMu.Lock()
+	k.workloadSelectors = selectors
+	k.workloadMu.Unlock()
+
+	return nil
+}
+
+// watchCronJobJobs watches Jobs in the configured CronJob's namespace and
+// refreshes k.workloadSelectors whenever one is added, updated, or removed,
+// so that scaled replacements (the next scheduled run's Job) are picked up
+// without restarting the observer.
+func (k *Kubernetes) watchCronJobJobs(ctx context.Context) {
+	w := k.workload
+
+	jobWatcher := kcache.NewListWatchFromClient(
+		k.kubeClient.BatchV1().RESTClient(), "jobs", w.namespace, kfields.Everything())
+
+	refresh := func() {
+		if err := k.refreshCronJobSelectors(ctx); err != nil {
+			log.Printf("[warning] failed to refresh cronjob %s/%s selectors: %v", w.namespace, w.name, err)
+		}
+	}
+
+	_, informer := kcache.NewInformer(jobWatcher, &kbatchv1.Job{}, 0, kcache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { refresh() },
+		UpdateFunc: func(interface{}, interface{}) { refresh() },
+		DeleteFunc: func(interface{}) { refresh() },
+	})
+
+	informer.Run(ctx.Done())
+}
+
+// podMatchesWorkload reports whether pod belongs to the configured CronJob
+// workload, by matching it against the current union of its child Jobs' pod
+// selectors. Workload kinds with a single selector are filtered server-side
+// via k.labelSelector instead, so this always returns true for them.
+func (k *Kubernetes) podMatchesWorkload(pod *kapi.Pod) bool {
+	return k.labelsMatchWorkload(pod.Labels)
+}
+
+// labelsMatchWorkload is the label-only core of podMatchesWorkload, usable
+// wherever only a set of labels -- not a full *kapi.Pod -- is available,
+// such as the CRI event stream's pod sandbox labels.
+func (k *Kubernetes) labelsMatchWorkload(labels map[string]string) bool {
+	if k.workload == nil || k.workload.kind != WorkloadCronJob {
+		return true
+	}
+
+	k.workloadMu.Lock()
+	selectors := k.workloadSelectors
+	k.workloadMu.Unlock()
+
+	set := klabels.Set(labels)
+
+	for _, sel := range selectors {
+		if sel.Matches(set) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isOwnedBy reports whether uid appears among refs, i.e. whether the object
+// owning refs is a child of the object identified by uid.
+func isOwnedBy(refs []metav1.OwnerReference, uid ktypes.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeRawSelector ANDs two label selector strings together, omitting
+// whichever side is empty.
+func mergeRawSelector(existing, additional string) string {
+	switch {
+	case existing == "":
+		return additional
+	case additional == "":
+		return existing
+	default:
+		return existing + "," + additional
+	}
+}
@@ -0,0 +1,59 @@
+package topology
+
+import (
+	"testing"
+
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+func TestMergeRawSelector(t *testing.T) {
+	cases := []struct {
+		name                 string
+		existing, additional string
+		want                 string
+	}{
+		{"both empty", "", "", ""},
+		{"existing only", "app=foo", "", "app=foo"},
+		{"additional only", "", "app=foo", "app=foo"},
+		{"both set are ANDed", "app=foo", "env=prod", "app=foo,env=prod"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := mergeRawSelector(tc.existing, tc.additional); got != tc.want {
+				t.Errorf("mergeRawSelector(%q, %q) = %q, want %q", tc.existing, tc.additional, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLabelsMatchWorkload(t *testing.T) {
+	sel, err := klabels.Parse("app=foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k := &Kubernetes{
+		workload:          &workload{kind: WorkloadCronJob},
+		workloadSelectors: []klabels.Selector{sel},
+	}
+
+	if !k.labelsMatchWorkload(map[string]string{"app": "foo"}) {
+		t.Error("expected labels matching a child Job's selector to match the workload")
+	}
+
+	if k.labelsMatchWorkload(map[string]string{"app": "bar"}) {
+		t.Error("expected labels not matching any child Job's selector to not match the workload")
+	}
+}
+
+func TestLabelsMatchWorkloadNonCronJob(t *testing.T) {
+	// Workload kinds with a single selector are filtered server-side via
+	// k.labelSelector instead, so labelsMatchWorkload is a no-op for them --
+	// and for no configured workload at all.
+	k := &Kubernetes{}
+
+	if !k.labelsMatchWorkload(map[string]string{"app": "anything"}) {
+		t.Error("expected labelsMatchWorkload to always match with no CronJob workload configured")
+	}
+}
@@ -0,0 +1,80 @@
+package topology
+
+import (
+	"context"
+	"os"
+)
+
+// RuntimeContainer is the runtime-agnostic view of a single running
+// container, as reported by whichever ContainerRuntime backend is
+// configured.
+type RuntimeContainer struct {
+	ID     string
+	Labels map[string]string
+	Image  string
+	Pid    int    // 0 if the backend didn't resolve it while listing; callers fall back to ContainerPID
+	PodUID string // populated by backends that can't recover pod name/namespace labels directly, e.g. the cgroup scan
+}
+
+// RuntimeFilter narrows ListRunning to the containers belonging to a single
+// pod, matched via its io.kubernetes.pod.name/namespace labels or, where a
+// backend can't read labels, its PodUID.
+type RuntimeFilter struct {
+	PodName      string
+	PodNamespace string
+	PodUID       string // used by backends that can only recover a container's owning pod by UID, e.g. the cgroup scan
+}
+
+// ContainerRuntime abstracts over the different ways a node's running
+// containers and their pids can be discovered. Kubernetes wiring
+// historically only spoke the CRI gRPC API, whose info.pid JSON blob is
+// only populated consistently by CRI-O and containerd's CRI plugin; on
+// nodes using dockershim replacements or rootless Podman this breaks
+// silently. Implementations exist for the CRI, native containerd and
+// Docker Engine APIs, and a last-resort cgroup scan.
+type ContainerRuntime interface {
+	// ListRunning returns every running container known to this runtime,
+	// optionally narrowed by filter.
+	ListRunning(ctx context.Context, filter *RuntimeFilter) ([]*RuntimeContainer, error)
+
+	// ContainerPID resolves the pid of a single running container by id.
+	ContainerPID(ctx context.Context, id string) (int, error)
+
+	// Close releases any resources (sockets, connections) held open by this
+	// runtime.
+	Close() error
+}
+
+// defaultRuntimeSockets are the well-known socket paths auto-detection
+// stats, in priority order, when no ContainerRuntime has been explicitly
+// configured and no CRI socket was set via WithKubernetesCRI.
+var defaultRuntimeSockets = []struct {
+	path string
+	new  func(path string) (ContainerRuntime, error)
+}{
+	{"/run/containerd/containerd.sock", newContainerdRuntime},
+	{"/var/run/containerd/containerd.sock", newContainerdRuntime},
+	{"/run/crio/crio.sock", newCRIRuntime},
+	{"/var/run/docker.sock", newDockerRuntime},
+}
+
+// detectContainerRuntime stats the well-known runtime socket paths in
+// priority order and connects to the first one found, falling back to a
+// cgroup scan if none is reachable.
+func detectContainerRuntime(procRoot string) ContainerRuntime {
+	for _, candidate := range defaultRuntimeSockets {
+		info, err := os.Stat(candidate.path)
+		if err != nil || info.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+
+		rt, err := candidate.new(candidate.path)
+		if err != nil {
+			continue
+		}
+
+		return rt
+	}
+
+	return newCgroupRuntime(procRoot)
+}
@@ -0,0 +1,130 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is the standard mountpoint for the cgroup hierarchy.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// containerCgroupRe extracts a container id from the trailing path
+// component of a kubepods cgroup, e.g.
+// ".../kubepods-burstable.slice/.../docker-<id>.scope" or
+// ".../kubepods/burstable/pod<uid>/<id>".
+var containerCgroupRe = regexp.MustCompile(`[:/-]([0-9a-fA-F]{64})(?:\.scope)?$`)
+
+// podUIDCgroupRe extracts a pod UID from a kubepods cgroup path, matching
+// both the systemd cgroup driver's underscore-separated form
+// (".../kubepods-burstable-pod<uid_with_underscores>.slice/...") and the
+// cgroupfs driver's dash-separated form
+// (".../kubepods/burstable/pod<uid>/...").
+var podUIDCgroupRe = regexp.MustCompile(`pod([0-9a-fA-F]{8}[_-][0-9a-fA-F]{4}[_-][0-9a-fA-F]{4}[_-][0-9a-fA-F]{4}[_-][0-9a-fA-F]{12})`)
+
+// podUIDFromCgroupPath recovers a pod's UID from its kubepods cgroup path,
+// normalizing the systemd driver's underscore-separated form to the
+// standard dash-separated UID so it compares equal to kapi.Pod.UID.
+func podUIDFromCgroupPath(path string) string {
+	m := podUIDCgroupRe.FindStringSubmatch(path)
+	if len(m) != 2 {
+		return ""
+	}
+
+	return strings.ReplaceAll(m[1], "_", "-")
+}
+
+// cgroupRuntime is the last-resort ContainerRuntime: when no runtime socket
+// is reachable, it recovers running container pids by walking the cgroup
+// hierarchy directly. It has no access to container labels or image
+// references, so a RuntimeFilter's PodName/PodNamespace can't be honored --
+// it instead recovers the owning pod's UID from the cgroup path itself and
+// matches on that, via RuntimeFilter.PodUID.
+type cgroupRuntime struct {
+	procRoot string
+}
+
+func newCgroupRuntime(procRoot string) ContainerRuntime {
+	return &cgroupRuntime{procRoot: procRoot}
+}
+
+func (r *cgroupRuntime) ListRunning(ctx context.Context, filter *RuntimeFilter) ([]*RuntimeContainer, error) {
+	var ret []*RuntimeContainer
+
+	err := filepath.Walk(cgroupRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// cgroup subtrees can disappear mid-walk as containers exit;
+			// don't let that abort the whole scan.
+			return nil
+		}
+
+		if info.IsDir() || info.Name() != "cgroup.procs" || !strings.Contains(path, "kubepods") {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+
+		id := containerCgroupRe.FindStringSubmatch(dir)
+		if len(id) != 2 {
+			return nil
+		}
+
+		podUID := podUIDFromCgroupPath(dir)
+		if filter != nil && filter.PodUID != "" && filter.PodUID != podUID {
+			return nil
+		}
+
+		pid, perr := firstPidFromCgroupProcs(path)
+		if perr != nil {
+			return nil
+		}
+
+		ret = append(ret, &RuntimeContainer{ID: id[1], Pid: pid, PodUID: podUID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *cgroupRuntime) ContainerPID(ctx context.Context, id string) (int, error) {
+	containers, err := r.ListRunning(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, c := range containers {
+		if strings.HasPrefix(c.ID, id) {
+			return c.Pid, nil
+		}
+	}
+
+	return -1, fmt.Errorf("no cgroup found for container '%s'", id)
+}
+
+func (r *cgroupRuntime) Close() error {
+	return nil
+}
+
+// firstPidFromCgroupProcs returns the first pid listed in a cgroup.procs
+// file, which is sufficient to resolve the container's pid namespace.
+func firstPidFromCgroupProcs(path string) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return -1, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return -1, fmt.Errorf("%s: no pids listed", path)
+	}
+
+	return strconv.Atoi(fields[0])
+}
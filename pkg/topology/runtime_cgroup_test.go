@@ -0,0 +1,35 @@
+package topology
+
+import "testing"
+
+func TestPodUIDFromCgroupPath(t *testing.T) {
+	cases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "systemd cgroup driver, underscore-separated",
+			path: "/sys/fs/cgroup/kubepods-burstable.slice/kubepods-burstable-pod6c692a6b_6c9a_4541_b1a9_9a9cd0b51409.slice/docker-abc123.scope",
+			want: "6c692a6b-6c9a-4541-b1a9-9a9cd0b51409",
+		},
+		{
+			name: "cgroupfs driver, dash-separated",
+			path: "/sys/fs/cgroup/kubepods/burstable/pod6c692a6b-6c9a-4541-b1a9-9a9cd0b51409/abc123",
+			want: "6c692a6b-6c9a-4541-b1a9-9a9cd0b51409",
+		},
+		{
+			name: "no pod UID present",
+			path: "/sys/fs/cgroup/system.slice/docker.service",
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := podUIDFromCgroupPath(tc.path); got != tc.want {
+				t.Errorf("podUIDFromCgroupPath(%q) = %q, want %q", tc.path, got, tc.want)
+			}
+		})
+	}
+}
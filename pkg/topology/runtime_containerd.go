@@ -0,0 +1,101 @@
+package topology
+
+import (
+	"context"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+)
+
+// containerdNamespace is the containerd namespace the kubelet's CRI plugin
+// places every pod/container in.
+const containerdNamespace = "k8s.io"
+
+// containerdRuntime implements ContainerRuntime against a native containerd
+// client, reading each container's pid directly from its Task instead of
+// relying on the CRI's info.pid JSON blob.
+type containerdRuntime struct {
+	client *containerd.Client
+}
+
+func newContainerdRuntime(socket string) (ContainerRuntime, error) {
+	client, err := containerd.New(socket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &containerdRuntime{client: client}, nil
+}
+
+func (r *containerdRuntime) ListRunning(ctx context.Context, filter *RuntimeFilter) ([]*RuntimeContainer, error) {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+
+	containers, err := r.client.Containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*RuntimeContainer, 0, len(containers))
+
+	for _, c := range containers {
+		labels, err := c.Labels(ctx)
+		if err != nil {
+			continue
+		}
+
+		if filter != nil {
+			pod := labels["io.kubernetes.pod.name"]
+			kns := labels["io.kubernetes.pod.namespace"]
+
+			if pod != filter.PodName || kns != filter.PodNamespace {
+				continue
+			}
+		}
+
+		task, err := c.Task(ctx, nil)
+		if err != nil {
+			// container exists but has no running task (created but not yet
+			// started, or already exited)
+			continue
+		}
+
+		taskStatus, err := task.Status(ctx)
+		if err != nil || taskStatus.Status != containerd.Running {
+			continue
+		}
+
+		info, err := c.Info(ctx)
+		if err != nil {
+			continue
+		}
+
+		ret = append(ret, &RuntimeContainer{
+			ID:     c.ID(),
+			Labels: labels,
+			Image:  info.Image,
+			Pid:    int(task.Pid()),
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *containerdRuntime) ContainerPID(ctx context.Context, id string) (int, error) {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+
+	c, err := r.client.LoadContainer(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	task, err := c.Task(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+
+	return int(task.Pid()), nil
+}
+
+func (r *containerdRuntime) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,118 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	pb "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// criRuntime implements ContainerRuntime against the CRI v1alpha2
+// RuntimeService gRPC API exposed by containerd's CRI plugin, CRI-O, and
+// similar runtimes.
+type criRuntime struct {
+	conn  *grpc.ClientConn
+	owned bool // true if this criRuntime dialed conn itself and is responsible for closing it
+}
+
+// newCRIRuntime dials a fresh connection to a CRI socket.
+func newCRIRuntime(socket string) (ContainerRuntime, error) {
+	conn, err := grpc.Dial(socket, grpc.WithInsecure(), grpc.WithContextDialer(
+		func(ctx context.Context, addr string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return &criRuntime{conn: conn, owned: true}, nil
+}
+
+// newCRIRuntimeFromConn wraps an existing CRI gRPC connection, letting
+// callers that already dialed one (e.g. Kubernetes's own k.criClient, used
+// for the CRI event stream) reuse it instead of opening a second connection
+// to the same socket. The wrapped connection is not closed by Close(), since
+// its lifecycle belongs to whoever dialed it.
+func newCRIRuntimeFromConn(conn *grpc.ClientConn) ContainerRuntime {
+	return &criRuntime{conn: conn}
+}
+
+func (r *criRuntime) ListRunning(ctx context.Context, filter *RuntimeFilter) ([]*RuntimeContainer, error) {
+	rpc := pb.NewRuntimeServiceClient(r.conn)
+
+	// we only care about containers that are marked as running
+	request := &pb.ListContainersRequest{
+		Filter: &pb.ContainerFilter{
+			State: &pb.ContainerStateValue{
+				State: pb.ContainerState_CONTAINER_RUNNING,
+			},
+		},
+	}
+
+	res, err := rpc.ListContainers(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := res.GetContainers()
+	ret := make([]*RuntimeContainer, 0, len(containers))
+
+	for _, container := range containers {
+		labels := container.GetLabels()
+
+		if filter != nil {
+			pod := labels["io.kubernetes.pod.name"]
+			kns := labels["io.kubernetes.pod.namespace"]
+
+			if pod != filter.PodName || kns != filter.PodNamespace {
+				continue
+			}
+		}
+
+		ret = append(ret, &RuntimeContainer{
+			ID:     container.GetId(),
+			Labels: labels,
+			Image:  container.GetImageRef(),
+		})
+	}
+
+	return ret, nil
+}
+
+// ContainerPID takes a container-id and attempts to find the PID of the
+// container using CRI from some of the meta-data found within the info
+// section of the response.
+func (r *criRuntime) ContainerPID(ctx context.Context, id string) (int, error) {
+	rpc := pb.NewRuntimeServiceClient(r.conn)
+	request := &pb.ContainerStatusRequest{ContainerId: id, Verbose: true}
+	response, err := rpc.ContainerStatus(ctx, request)
+
+	if err != nil {
+		return -1, err
+	}
+
+	rawinfo := response.GetInfo()
+	info := make(map[string]interface{})
+
+	if err := json.Unmarshal([]byte(rawinfo["info"]), &info); err != nil {
+		return -1, err
+	}
+
+	if rawpid, ok := info["pid"]; ok {
+		return int(rawpid.(float64)), nil
+	}
+
+	return -1, errors.New("no pid found in info response")
+}
+
+func (r *criRuntime) Close() error {
+	if r.owned && r.conn != nil {
+		return r.conn.Close()
+	}
+
+	return nil
+}
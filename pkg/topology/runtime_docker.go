@@ -0,0 +1,71 @@
+package topology
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// dockerRuntime implements ContainerRuntime against the Docker Engine API,
+// for nodes running a dockershim-replacement kubelet configuration where the
+// CRI's info.pid blob isn't populated.
+type dockerRuntime struct {
+	client *client.Client
+}
+
+func newDockerRuntime(socket string) (ContainerRuntime, error) {
+	cli, err := client.NewClientWithOpts(client.WithHost("unix://"+socket), client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerRuntime{client: cli}, nil
+}
+
+func (r *dockerRuntime) ListRunning(ctx context.Context, filter *RuntimeFilter) ([]*RuntimeContainer, error) {
+	containers, err := r.client.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*RuntimeContainer, 0, len(containers))
+
+	for _, c := range containers {
+		if filter != nil {
+			pod := c.Labels["io.kubernetes.pod.name"]
+			kns := c.Labels["io.kubernetes.pod.namespace"]
+
+			if pod != filter.PodName || kns != filter.PodNamespace {
+				continue
+			}
+		}
+
+		inspect, err := r.client.ContainerInspect(ctx, c.ID)
+		if err != nil || inspect.State == nil || !inspect.State.Running {
+			continue
+		}
+
+		ret = append(ret, &RuntimeContainer{
+			ID:     c.ID,
+			Labels: c.Labels,
+			Image:  c.Image,
+			Pid:    inspect.State.Pid,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *dockerRuntime) ContainerPID(ctx context.Context, id string) (int, error) {
+	inspect, err := r.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	return inspect.State.Pid, nil
+}
+
+func (r *dockerRuntime) Close() error {
+	return r.client.Close()
+}
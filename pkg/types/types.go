@@ -0,0 +1,27 @@
+// Package types contains the data types shared between swoll's topology
+// observers and the rest of the codebase.
+package types
+
+// Kind classifies a container by its role within a pod.
+type Kind string
+
+const (
+	KindSandbox   Kind = "Sandbox"   // the pod's pause/infra container
+	KindInit      Kind = "Init"      // a container in pod.Spec.InitContainers
+	KindApp       Kind = "App"       // a regular workload container
+	KindEphemeral Kind = "Ephemeral" // a `kubectl debug` ephemeral container
+)
+
+// Container is the runtime-agnostic, already-resolved view of a single
+// running container, as produced by a topology Observer.
+type Container struct {
+	ID           string
+	Labels       map[string]string
+	Image        string
+	Pod          string
+	Namespace    string
+	Name         string
+	Pid          int
+	PidNamespace int
+	Kind         Kind // the container's role within its pod, if the observer could classify it
+}